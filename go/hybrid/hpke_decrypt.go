@@ -0,0 +1,60 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/internal/hpke"
+	"github.com/google/tink/go/tink"
+)
+
+// hpkeDecrypt is a tink.HybridDecrypt implementation for HPKE mode_base
+// (RFC 9180 Section 5.1.1, SetupBaseR).
+type hpkeDecrypt struct {
+	recipientPrivateKey []byte
+	params              hpke.Params
+}
+
+func newHPKEDecrypt(recipientPrivateKey []byte, params hpke.Params) (tink.HybridDecrypt, error) {
+	return &hpkeDecrypt{recipientPrivateKey: recipientPrivateKey, params: params}, nil
+}
+
+// Decrypt implements tink.HybridDecrypt. It splits ciphertext into the
+// KEM's Nenc-byte encapsulated key and the trailing AEAD ciphertext,
+// rejecting anything shorter than Nenc, then runs SetupBaseR and opens with
+// contextInfo reused as both HPKE's "info" and the AEAD associated data, to
+// match hpkeEncrypt.
+func (d *hpkeDecrypt) Decrypt(ciphertext, contextInfo []byte) ([]byte, error) {
+	nEnc, err := hpke.EncapsulatedKeySize(d.params.KEMID)
+	if err != nil {
+		return nil, fmt.Errorf("hpke_decrypt: %s", err)
+	}
+	if len(ciphertext) < nEnc {
+		return nil, fmt.Errorf("hpke_decrypt: ciphertext of length %d is shorter than the encapsulated key length %d", len(ciphertext), nEnc)
+	}
+	enc, aeadCiphertext := ciphertext[:nEnc], ciphertext[nEnc:]
+
+	context, err := hpke.SetupBaseR(d.params, enc, d.recipientPrivateKey, contextInfo)
+	if err != nil {
+		return nil, fmt.Errorf("hpke_decrypt: %s", err)
+	}
+
+	plaintext, err := context.Open(contextInfo, aeadCiphertext)
+	if err != nil {
+		return nil, fmt.Errorf("hpke_decrypt: %s", err)
+	}
+	return plaintext, nil
+}
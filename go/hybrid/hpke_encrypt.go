@@ -0,0 +1,51 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/internal/hpke"
+	"github.com/google/tink/go/tink"
+)
+
+// hpkeEncrypt is a tink.HybridEncrypt implementation for HPKE mode_base
+// (RFC 9180 Section 5.1.1, SetupBaseS).
+type hpkeEncrypt struct {
+	recipientPublicKey []byte
+	params             hpke.Params
+}
+
+func newHPKEEncrypt(recipientPublicKey []byte, params hpke.Params) (tink.HybridEncrypt, error) {
+	return &hpkeEncrypt{recipientPublicKey: recipientPublicKey, params: params}, nil
+}
+
+// Encrypt implements tink.HybridEncrypt. contextInfo is bound to the
+// ciphertext both as HPKE's "info" (RFC 9180 Section 5.1) and, reused, as
+// the sole AEAD seal's associated data. The returned ciphertext is
+// enc || AEAD-ciphertext, per RFC 9180 Section 6.1's "single-shot" layout.
+func (e *hpkeEncrypt) Encrypt(plaintext, contextInfo []byte) ([]byte, error) {
+	enc, context, err := hpke.SetupBaseS(e.params, e.recipientPublicKey, contextInfo)
+	if err != nil {
+		return nil, fmt.Errorf("hpke_encrypt: %s", err)
+	}
+
+	ciphertext, err := context.Seal(contextInfo, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("hpke_encrypt: %s", err)
+	}
+
+	return append(enc, ciphertext...), nil
+}
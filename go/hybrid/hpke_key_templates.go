@@ -0,0 +1,70 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	hpkepb "github.com/google/tink/go/proto/hpke_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// HPKEX25519HKDFSHA256Aes128GCMKeyTemplate returns a KeyTemplate for HPKE
+// with DHKEM(X25519, HKDF-SHA256), HKDF-SHA256 and AES-128-GCM.
+func HPKEX25519HKDFSHA256Aes128GCMKeyTemplate() *tinkpb.KeyTemplate {
+	return hpkeKeyTemplate(hpkepb.HpkeKem_DHKEM_X25519_HKDF_SHA256, hpkepb.HpkeKdf_HKDF_SHA256, hpkepb.HpkeAead_AES_128_GCM)
+}
+
+// HPKEX25519HKDFSHA256Aes256GCMKeyTemplate returns a KeyTemplate for HPKE
+// with DHKEM(X25519, HKDF-SHA256), HKDF-SHA256 and AES-256-GCM.
+func HPKEX25519HKDFSHA256Aes256GCMKeyTemplate() *tinkpb.KeyTemplate {
+	return hpkeKeyTemplate(hpkepb.HpkeKem_DHKEM_X25519_HKDF_SHA256, hpkepb.HpkeKdf_HKDF_SHA256, hpkepb.HpkeAead_AES_256_GCM)
+}
+
+// HPKEX25519HKDFSHA256ChaCha20Poly1305KeyTemplate returns a KeyTemplate for
+// HPKE with DHKEM(X25519, HKDF-SHA256), HKDF-SHA256 and ChaCha20Poly1305.
+func HPKEX25519HKDFSHA256ChaCha20Poly1305KeyTemplate() *tinkpb.KeyTemplate {
+	return hpkeKeyTemplate(hpkepb.HpkeKem_DHKEM_X25519_HKDF_SHA256, hpkepb.HpkeKdf_HKDF_SHA256, hpkepb.HpkeAead_CHACHA20_POLY1305)
+}
+
+// HPKEP256HKDFSHA256Aes128GCMKeyTemplate returns a KeyTemplate for HPKE with
+// DHKEM(P-256, HKDF-SHA256), HKDF-SHA256 and AES-128-GCM.
+func HPKEP256HKDFSHA256Aes128GCMKeyTemplate() *tinkpb.KeyTemplate {
+	return hpkeKeyTemplate(hpkepb.HpkeKem_DHKEM_P256_HKDF_SHA256, hpkepb.HpkeKdf_HKDF_SHA256, hpkepb.HpkeAead_AES_128_GCM)
+}
+
+// HPKEP256HKDFSHA256Aes256GCMKeyTemplate returns a KeyTemplate for HPKE with
+// DHKEM(P-256, HKDF-SHA256), HKDF-SHA256 and AES-256-GCM.
+func HPKEP256HKDFSHA256Aes256GCMKeyTemplate() *tinkpb.KeyTemplate {
+	return hpkeKeyTemplate(hpkepb.HpkeKem_DHKEM_P256_HKDF_SHA256, hpkepb.HpkeKdf_HKDF_SHA256, hpkepb.HpkeAead_AES_256_GCM)
+}
+
+func hpkeKeyTemplate(kem hpkepb.HpkeKem, kdf hpkepb.HpkeKdf, aead hpkepb.HpkeAead) *tinkpb.KeyTemplate {
+	format := &hpkepb.HpkeKeyFormat{
+		Params: &hpkepb.HpkeParams{
+			Kem:  kem,
+			Kdf:  kdf,
+			Aead: aead,
+		},
+	}
+	serializedFormat, err := proto.Marshal(format)
+	if err != nil {
+		panic("failed to marshal HpkeKeyFormat proto")
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          hpkePrivateKeyTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
@@ -0,0 +1,177 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+	hpkepb "github.com/google/tink/go/proto/hpke_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	hpkePrivateKeyVersion = 0
+	hpkePrivateKeyTypeURL = "type.googleapis.com/google.crypto.tink.HpkePrivateKey"
+)
+
+var errInvalidHPKEPrivateKey = errors.New("hpke_private_key_manager: invalid key")
+var errInvalidHPKEKeyFormat = errors.New("hpke_private_key_manager: invalid key format")
+
+func init() {
+	if err := registry.RegisterKeyManager(newHPKEPrivateKeyManager()); err != nil {
+		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
+	}
+}
+
+type hpkePrivateKeyManager struct{}
+
+var _ registry.PrivateKeyManager = (*hpkePrivateKeyManager)(nil)
+
+// newHPKEPrivateKeyManager creates a new hpkePrivateKeyManager.
+func newHPKEPrivateKeyManager() *hpkePrivateKeyManager {
+	return new(hpkePrivateKeyManager)
+}
+
+// Primitive unmarshals serializedKey into an HpkePrivateKey proto and
+// returns the corresponding tink.HybridDecrypt primitive.
+func (km *hpkePrivateKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidHPKEPrivateKey
+	}
+	key := new(hpkepb.HpkePrivateKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidHPKEPrivateKey
+	}
+	if err := validateHPKEPrivateKey(key); err != nil {
+		return nil, err
+	}
+
+	params, err := hpkeParams(key.GetPublicKey().GetParams())
+	if err != nil {
+		return nil, fmt.Errorf("hpke_private_key_manager: %s", err)
+	}
+	return newHPKEDecrypt(key.GetPrivateKey(), params)
+}
+
+// NewKey generates a fresh HPKE key pair for the KEM named in
+// serializedKeyFormat's params.
+func (km *hpkePrivateKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidHPKEKeyFormat
+	}
+	keyFormat := new(hpkepb.HpkeKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidHPKEKeyFormat
+	}
+
+	privateKeyBytes, publicKeyBytes, err := generateHPKEKeyPair(keyFormat.GetParams().GetKem())
+	if err != nil {
+		return nil, fmt.Errorf("hpke_private_key_manager: %s", err)
+	}
+
+	return &hpkepb.HpkePrivateKey{
+		Version: hpkePrivateKeyVersion,
+		PublicKey: &hpkepb.HpkePublicKey{
+			Version:   hpkePublicKeyVersion,
+			Params:    keyFormat.GetParams(),
+			PublicKey: publicKeyBytes,
+		},
+		PrivateKey: privateKeyBytes,
+	}, nil
+}
+
+// NewKeyData generates a new key per NewKey and wraps it as a tinkpb.KeyData
+// of type asymmetric private key, which keyset handles use to look up the
+// corresponding public key via PublicKeyData.
+func (km *hpkePrivateKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         hpkePrivateKeyTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key embedded in serializedPrivKey and
+// wraps it as a tinkpb.KeyData of type asymmetric public key.
+func (km *hpkePrivateKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	key := new(hpkepb.HpkePrivateKey)
+	if err := proto.Unmarshal(serializedPrivKey, key); err != nil {
+		return nil, errInvalidHPKEPrivateKey
+	}
+	if err := validateHPKEPrivateKey(key); err != nil {
+		return nil, err
+	}
+
+	serializedPubKey, err := proto.Marshal(key.GetPublicKey())
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         hpkePublicKeyTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+func (km *hpkePrivateKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == hpkePrivateKeyTypeURL
+}
+
+func (km *hpkePrivateKeyManager) TypeURL() string {
+	return hpkePrivateKeyTypeURL
+}
+
+func validateHPKEPrivateKey(key *hpkepb.HpkePrivateKey) error {
+	if key.GetVersion() != hpkePrivateKeyVersion {
+		return fmt.Errorf("hpke_private_key_manager: unsupported key version %d", key.GetVersion())
+	}
+	if len(key.GetPrivateKey()) == 0 {
+		return errInvalidHPKEPrivateKey
+	}
+	return validateHPKEPublicKey(key.GetPublicKey())
+}
+
+// generateHPKEKeyPair generates a fresh private/public key pair for the
+// given KEM, matching the curve that kem.go selects for the same KEM ID.
+func generateHPKEKeyPair(kem hpkepb.HpkeKem) (privateKey, publicKey []byte, err error) {
+	var curve ecdh.Curve
+	switch kem {
+	case hpkepb.HpkeKem_DHKEM_X25519_HKDF_SHA256:
+		curve = ecdh.X25519()
+	case hpkepb.HpkeKem_DHKEM_P256_HKDF_SHA256:
+		curve = ecdh.P256()
+	default:
+		return nil, nil, fmt.Errorf("hpke: unsupported KEM %s", kem)
+	}
+
+	priv, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %s", err)
+	}
+	return priv.Bytes(), priv.PublicKey().Bytes(), nil
+}
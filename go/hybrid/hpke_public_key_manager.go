@@ -0,0 +1,101 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+	hpkepb "github.com/google/tink/go/proto/hpke_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	hpkePublicKeyVersion = 0
+	hpkePublicKeyTypeURL = "type.googleapis.com/google.crypto.tink.HpkePublicKey"
+)
+
+var errInvalidHPKEPublicKey = errors.New("hpke_public_key_manager: invalid key")
+
+func init() {
+	if err := registry.RegisterKeyManager(newHPKEPublicKeyManager()); err != nil {
+		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
+	}
+}
+
+type hpkePublicKeyManager struct{}
+
+var _ registry.KeyManager = (*hpkePublicKeyManager)(nil)
+
+// newHPKEPublicKeyManager creates a new hpkePublicKeyManager.
+func newHPKEPublicKeyManager() *hpkePublicKeyManager {
+	return new(hpkePublicKeyManager)
+}
+
+// Primitive unmarshals serializedKey into an HpkePublicKey proto and returns
+// the corresponding tink.HybridEncrypt primitive.
+func (km *hpkePublicKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidHPKEPublicKey
+	}
+	key := new(hpkepb.HpkePublicKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidHPKEPublicKey
+	}
+	if err := validateHPKEPublicKey(key); err != nil {
+		return nil, err
+	}
+
+	params, err := hpkeParams(key.GetParams())
+	if err != nil {
+		return nil, fmt.Errorf("hpke_public_key_manager: %s", err)
+	}
+	return newHPKEEncrypt(key.GetPublicKey(), params)
+}
+
+// NewKey is not supported: HPKE public keys are only ever derived from a
+// private key, never generated directly, mirroring the other asymmetric
+// public key managers in this package.
+func (km *hpkePublicKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, fmt.Errorf("hpke_public_key_manager: NewKey not supported")
+}
+
+// NewKeyData is not supported for the same reason as NewKey.
+func (km *hpkePublicKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, fmt.Errorf("hpke_public_key_manager: NewKeyData not supported")
+}
+
+func (km *hpkePublicKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == hpkePublicKeyTypeURL
+}
+
+func (km *hpkePublicKeyManager) TypeURL() string {
+	return hpkePublicKeyTypeURL
+}
+
+func validateHPKEPublicKey(key *hpkepb.HpkePublicKey) error {
+	if key.GetVersion() != hpkePublicKeyVersion {
+		return fmt.Errorf("hpke_public_key_manager: unsupported key version %d", key.GetVersion())
+	}
+	if len(key.GetPublicKey()) == 0 {
+		return errInvalidHPKEPublicKey
+	}
+	if _, err := hpkeParams(key.GetParams()); err != nil {
+		return fmt.Errorf("hpke_public_key_manager: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/internal/hpke"
+	hpkepb "github.com/google/tink/go/proto/hpke_go_proto"
+)
+
+// hpkeParams converts the wire HpkeParams proto into the internal/hpke
+// package's Params, which the factories pass around as plain uint16 IDs.
+func hpkeParams(p *hpkepb.HpkeParams) (hpke.Params, error) {
+	kemID, err := hpkeKEMID(p.GetKem())
+	if err != nil {
+		return hpke.Params{}, err
+	}
+	kdfID, err := hpkeKDFID(p.GetKdf())
+	if err != nil {
+		return hpke.Params{}, err
+	}
+	aeadID, err := hpkeAEADID(p.GetAead())
+	if err != nil {
+		return hpke.Params{}, err
+	}
+	return hpke.Params{KEMID: kemID, KDFID: kdfID, AEADID: aeadID}, nil
+}
+
+func hpkeKEMID(kem hpkepb.HpkeKem) (uint16, error) {
+	switch kem {
+	case hpkepb.HpkeKem_DHKEM_X25519_HKDF_SHA256:
+		return hpke.X25519HKDFSHA256, nil
+	case hpkepb.HpkeKem_DHKEM_P256_HKDF_SHA256:
+		return hpke.P256HKDFSHA256, nil
+	default:
+		return 0, fmt.Errorf("hpke: unsupported KEM %s", kem)
+	}
+}
+
+func hpkeKDFID(kdf hpkepb.HpkeKdf) (uint16, error) {
+	switch kdf {
+	case hpkepb.HpkeKdf_HKDF_SHA256:
+		return hpke.HKDFSHA256, nil
+	case hpkepb.HpkeKdf_HKDF_SHA384:
+		return hpke.HKDFSHA384, nil
+	case hpkepb.HpkeKdf_HKDF_SHA512:
+		return hpke.HKDFSHA512, nil
+	default:
+		return 0, fmt.Errorf("hpke: unsupported KDF %s", kdf)
+	}
+}
+
+func hpkeAEADID(aead hpkepb.HpkeAead) (uint16, error) {
+	switch aead {
+	case hpkepb.HpkeAead_AES_128_GCM:
+		return hpke.AES128GCM, nil
+	case hpkepb.HpkeAead_AES_256_GCM:
+		return hpke.AES256GCM, nil
+	case hpkepb.HpkeAead_CHACHA20_POLY1305:
+		return hpke.ChaCha20Poly1305, nil
+	default:
+		return 0, fmt.Errorf("hpke: unsupported AEAD %s", aead)
+	}
+}
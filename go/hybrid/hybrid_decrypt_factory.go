@@ -15,6 +15,7 @@
 package hybrid
 
 import (
+	"encoding/binary"
 	"fmt"
 	"io"
 
@@ -25,15 +26,29 @@ import (
 	"github.com/google/tink/go/tink"
 )
 
+// KeyIDHybridDecrypt is a tink.HybridDecrypt that can also be directed to
+// decrypt with a specific, caller-known key ID instead of trying every
+// matching entry in the keyset. NewHybridDecrypt and
+// NewHybridDecryptWithKeyManager return a primitive satisfying this
+// interface, so callers that want the key-ID fast path can type-assert for
+// it instead of hand-rolling their own local interface.
+type KeyIDHybridDecrypt interface {
+	tink.HybridDecrypt
+
+	// DecryptWithKeyID decrypts ciphertext as Decrypt does, but only tries
+	// the entry (or entries) whose key ID equals keyID.
+	DecryptWithKeyID(ciphertext, contextInfo []byte, keyID uint32) ([]byte, error)
+}
+
 // NewHybridDecrypt returns an HybridDecrypt primitive from the given keyset handle.
-func NewHybridDecrypt(h *keyset.Handle) (tink.HybridDecrypt, error) {
+func NewHybridDecrypt(h *keyset.Handle) (KeyIDHybridDecrypt, error) {
 	return NewHybridDecryptWithKeyManager(h, nil /*keyManager*/)
 }
 
 // NewHybridDecryptWithKeyManager returns an HybridDecrypt primitive from the given keyset handle
 // and custom key manager.
 // Deprecated: register the KeyManager and use New above.
-func NewHybridDecryptWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.HybridDecrypt, error) {
+func NewHybridDecryptWithKeyManager(h *keyset.Handle, km registry.KeyManager) (KeyIDHybridDecrypt, error) {
 	ps, err := h.PrimitivesWithKeyManager(km)
 	if err != nil {
 		return nil, fmt.Errorf("hybrid_factory: cannot obtain primitive set: %s", err)
@@ -46,23 +61,34 @@ func NewHybridDecryptWithKeyManager(h *keyset.Handle, km registry.KeyManager) (t
 // for decryption.
 type wrappedHybridDecrypt struct {
 	ps *primitiveset.PrimitiveSet
+	// byKeyID indexes ps's entries by key ID so that both the key-ID hint
+	// path (DecryptWithKeyID) and the non-raw prefix path in Decrypt can
+	// look up the handful of entries for a ciphertext's key ID directly,
+	// instead of scanning every entry matching a prefix or, worse, every
+	// raw entry in the keyset.
+	byKeyID map[uint32][]*primitiveset.Entry
 }
 
+var _ KeyIDHybridDecrypt = (*wrappedHybridDecrypt)(nil)
+
 func newWrappedHybridDecrypt(ps *primitiveset.PrimitiveSet) (*wrappedHybridDecrypt, error) {
 	if _, ok := (ps.Primary.Primitive).(tink.HybridDecrypt); !ok {
 		return nil, fmt.Errorf("hybrid_factory: not a HybridDecrypt primitive")
 	}
 
+	byKeyID := make(map[uint32][]*primitiveset.Entry)
 	for _, primitives := range ps.Entries {
 		for _, p := range primitives {
 			if _, ok := (p.Primitive).(tink.HybridDecrypt); !ok {
 				return nil, fmt.Errorf("hybrid_factory: not a HybridDecrypt primitive")
 			}
+			byKeyID[p.KeyID] = append(byKeyID[p.KeyID], p)
 		}
 	}
 
 	ret := new(wrappedHybridDecrypt)
 	ret.ps = ps
+	ret.byKeyID = byKeyID
 
 	return ret, nil
 }
@@ -76,18 +102,24 @@ func (a *wrappedHybridDecrypt) Decrypt(ct, ad []byte) ([]byte, error) {
 	if len(ct) > prefixSize {
 		prefix := ct[:prefixSize]
 		ctNoPrefix := ct[prefixSize:]
-		entries, err := a.ps.EntriesForPrefix(string(prefix))
-		if err == nil {
-			for i := 0; i < len(entries); i++ {
-				p, ok := (entries[i].Primitive).(tink.HybridDecrypt)
-				if !ok {
-					return nil, fmt.Errorf("hybrid_factory: not a HybridDecrypt primitive")
-				}
-
-				pt, err := p.Decrypt(ctNoPrefix, ad)
-				if err == nil {
-					return pt, nil
-				}
+		// The non-raw prefix already encodes the key ID, so look the
+		// entries up directly in the key ID index rather than scanning
+		// EntriesForPrefix's result, which is O(N) in the number of keys
+		// sharing that prefix family.
+		keyID := binary.BigEndian.Uint32(prefix[1:prefixSize])
+		for _, e := range a.byKeyID[keyID] {
+			if e.Prefix != string(prefix) {
+				continue
+			}
+
+			p, ok := (e.Primitive).(tink.HybridDecrypt)
+			if !ok {
+				return nil, fmt.Errorf("hybrid_factory: not a HybridDecrypt primitive")
+			}
+
+			pt, err := p.Decrypt(ctNoPrefix, ad)
+			if err == nil {
+				return pt, nil
 			}
 		}
 	}
@@ -112,6 +144,43 @@ func (a *wrappedHybridDecrypt) Decrypt(ct, ad []byte) ([]byte, error) {
 	return nil, fmt.Errorf("hybrid_factory: decryption failed")
 }
 
+// DecryptWithKeyID decrypts ct as Decrypt does, but only tries entries whose
+// key ID equals keyID, looked up directly in the byKeyID index built by
+// newWrappedHybridDecrypt instead of scanning the prefix-matching and raw-key
+// entries.
+// Use it when the caller already knows which key produced ct (e.g. it was
+// carried alongside the ciphertext out of band) to avoid the O(N) AEAD
+// attempts that Decrypt's raw-key fallback can incur on keysets with many
+// raw keys.
+func (a *wrappedHybridDecrypt) DecryptWithKeyID(ct, ad []byte, keyID uint32) ([]byte, error) {
+	entries, ok := a.byKeyID[keyID]
+	if !ok {
+		return nil, fmt.Errorf("hybrid_factory: no entry found for key id %d", keyID)
+	}
+
+	for _, e := range entries {
+		p, ok := (e.Primitive).(tink.HybridDecrypt)
+		if !ok {
+			return nil, fmt.Errorf("hybrid_factory: not a HybridDecrypt primitive")
+		}
+
+		ctNoPrefix := ct
+		if len(e.Prefix) > 0 {
+			if len(ct) < len(e.Prefix) || string(ct[:len(e.Prefix)]) != e.Prefix {
+				continue
+			}
+			ctNoPrefix = ct[len(e.Prefix):]
+		}
+
+		pt, err := p.Decrypt(ctNoPrefix, ad)
+		if err == nil {
+			return pt, nil
+		}
+	}
+
+	return nil, fmt.Errorf("hybrid_factory: decryption failed for key id %d", keyID)
+}
+
 type StreamingHybridDecrypt interface {
 	NewDecryptingReader(r io.Reader, contextInfo []byte) (io.Reader, error)
 }
@@ -159,5 +228,83 @@ func (wshd *wrappedStreamingHybridDecrypt) NewDecryptingReader(w io.Reader, cont
 		return nil, fmt.Errorf("streaminghybrid_factory.go: %T is not a StreamingHybridDecrypt primitive", primary.Primitive)
 	}
 
+	if len(primary.Prefix) > 0 {
+		gotPrefix := make([]byte, len(primary.Prefix))
+		if _, err := io.ReadFull(w, gotPrefix); err != nil {
+			return nil, fmt.Errorf("streaminghybrid_factory.go: failed to read key prefix: %s", err)
+		}
+		if string(gotPrefix) != primary.Prefix {
+			return nil, fmt.Errorf("streaminghybrid_factory.go: ciphertext prefix does not match primary key's prefix")
+		}
+	}
+
 	return p.NewDecryptingReader(w, contextInfo)
 }
+
+// StreamingHybridEncrypt is a stream cipher for hybrid encryption.
+//
+// Hybrid encryption combines the efficiency of symmetric encryption with the
+// convenience of public-key encryption: a symmetric streaming AEAD key is
+// derived for the recipient's public key, and that key is used to encrypt
+// the plaintext as it is streamed through the returned writer.
+type StreamingHybridEncrypt interface {
+	// NewEncryptingWriter returns a wrapper around underlying io.Writer w, such
+	// that any write-operation via the wrapper results in AEAD-encryption of
+	// the written data, using contextInfo as associated authenticated data.
+	// The associated data is not included in the ciphertext and has to be
+	// passed in as parameter for decryption.
+	NewEncryptingWriter(w io.Writer, contextInfo []byte) (io.WriteCloser, error)
+}
+
+// NewStreamingHybridEncrypt returns a StreamingHybridEncrypt primitive from the given keyset handle.
+func NewStreamingHybridEncrypt(h *keyset.Handle) (StreamingHybridEncrypt, error) {
+	return NewStreamingHybridEncryptWithKeyManager(h, nil /*keyManager*/)
+}
+
+// NewStreamingHybridEncryptWithKeyManager returns a StreamingHybridEncrypt primitive from the given
+// keyset handle and custom key manager.
+func NewStreamingHybridEncryptWithKeyManager(h *keyset.Handle, km registry.KeyManager) (StreamingHybridEncrypt, error) {
+	ps, err := h.PrimitivesWithKeyManager(km)
+	if err != nil {
+		return nil, fmt.Errorf("hybrid_factory: cannot obtain primitive set: %s", err)
+	}
+
+	return newStreamingHybridEncryptPrimitiveSet(ps)
+}
+
+type wrappedStreamingHybridEncrypt struct {
+	ps *primitiveset.PrimitiveSet
+}
+
+func newStreamingHybridEncryptPrimitiveSet(ps *primitiveset.PrimitiveSet) (*wrappedStreamingHybridEncrypt, error) {
+	if _, ok := (ps.Primary.Primitive).(StreamingHybridEncrypt); !ok {
+		return nil, fmt.Errorf("hybrid_factory: primary %T is not a StreamingHybridEncrypt primitive", ps.Primary.Primitive)
+	}
+
+	for _, primitives := range ps.Entries {
+		for _, p := range primitives {
+			if _, ok := (p.Primitive).(StreamingHybridEncrypt); !ok {
+				return nil, fmt.Errorf("hybrid_factory: entry %T is not a StreamingHybridEncrypt primitive", p.Primitive)
+			}
+		}
+	}
+
+	ret := new(wrappedStreamingHybridEncrypt)
+	ret.ps = ps
+
+	return ret, nil
+}
+
+func (wshe *wrappedStreamingHybridEncrypt) NewEncryptingWriter(w io.Writer, contextInfo []byte) (io.WriteCloser, error) {
+	primary := wshe.ps.Primary
+	p, ok := (primary.Primitive).(StreamingHybridEncrypt)
+	if !ok {
+		return nil, fmt.Errorf("streaminghybrid_factory.go: %T is not a StreamingHybridEncrypt primitive", primary.Primitive)
+	}
+
+	if _, err := w.Write([]byte(primary.Prefix)); err != nil {
+		return nil, fmt.Errorf("streaminghybrid_factory.go: failed to write key prefix: %s", err)
+	}
+
+	return p.NewEncryptingWriter(w, contextInfo)
+}
@@ -0,0 +1,200 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/google/tink/go/core/primitiveset"
+)
+
+// fakeHybridDecrypt is a tink.HybridDecrypt primitive that "decrypts"
+// ciphertext produced by stripping a fixed prefix and checking it equals
+// wantPlaintext, independent of any real AEAD. It exists only to exercise
+// wrappedHybridDecrypt's own key-ID indexing and prefix handling.
+type fakeHybridDecrypt struct {
+	wantPlaintext []byte
+}
+
+func (f fakeHybridDecrypt) Decrypt(ct, ad []byte) ([]byte, error) {
+	if !bytes.Equal(ct, f.wantPlaintext) {
+		return nil, fmt.Errorf("fakeHybridDecrypt: ciphertext does not match")
+	}
+	return ct, nil
+}
+
+// newFakeHybridPrimitiveSet builds a primitive set with a single non-raw
+// entry for keyID with the given prefix, wrapping a fakeHybridDecrypt that
+// only "decrypts" ciphertext equal to wantPlaintext (once the prefix has
+// been stripped).
+func newFakeHybridPrimitiveSet(prefix string, keyID uint32, wantPlaintext []byte) *primitiveset.PrimitiveSet {
+	entry := &primitiveset.Entry{
+		KeyID:     keyID,
+		Primitive: fakeHybridDecrypt{wantPlaintext: wantPlaintext},
+		Prefix:    prefix,
+	}
+	return &primitiveset.PrimitiveSet{
+		Primary: entry,
+		Entries: map[string][]*primitiveset.Entry{prefix: {entry}},
+	}
+}
+
+// fakeStreamingHybrid is a StreamingHybridEncrypt/StreamingHybridDecrypt
+// primitive that passes ciphertext through unmodified. It exists only to
+// exercise wrappedStreamingHybridEncrypt/wrappedStreamingHybridDecrypt's own
+// prefix handling, independent of any real AEAD.
+type fakeStreamingHybrid struct{}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func (fakeStreamingHybrid) NewEncryptingWriter(w io.Writer, contextInfo []byte) (io.WriteCloser, error) {
+	return nopWriteCloser{w}, nil
+}
+
+func (fakeStreamingHybrid) NewDecryptingReader(r io.Reader, contextInfo []byte) (io.Reader, error) {
+	return r, nil
+}
+
+func newFakeStreamingPrimitiveSet(prefix string) *primitiveset.PrimitiveSet {
+	entry := &primitiveset.Entry{
+		KeyID:     1,
+		Primitive: fakeStreamingHybrid{},
+		Prefix:    prefix,
+	}
+	return &primitiveset.PrimitiveSet{
+		Primary: entry,
+		Entries: map[string][]*primitiveset.Entry{prefix: {entry}},
+	}
+}
+
+// TestStreamingHybridEncryptDecryptRoundTrip checks that ciphertext produced
+// by wrappedStreamingHybridEncrypt.NewEncryptingWriter can be read back by
+// wrappedStreamingHybridDecrypt.NewDecryptingReader, i.e. that the prefix
+// the encrypt side writes is the prefix the decrypt side strips.
+func TestStreamingHybridEncryptDecryptRoundTrip(t *testing.T) {
+	ps := newFakeStreamingPrimitiveSet("abcde")
+	wshe, err := newStreamingHybridEncryptPrimitiveSet(ps)
+	if err != nil {
+		t.Fatalf("newStreamingHybridEncryptPrimitiveSet() failed: %s", err)
+	}
+	wshd, err := newStreamingHybridDecryptPrimitiveSet(ps)
+	if err != nil {
+		t.Fatalf("newStreamingHybridDecryptPrimitiveSet() failed: %s", err)
+	}
+
+	contextInfo := []byte("context-info")
+	plaintext := []byte("hello streaming hybrid world")
+
+	var ciphertext bytes.Buffer
+	w, err := wshe.NewEncryptingWriter(&ciphertext, contextInfo)
+	if err != nil {
+		t.Fatalf("NewEncryptingWriter() failed: %s", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		t.Fatalf("Write() failed: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %s", err)
+	}
+
+	r, err := wshd.NewDecryptingReader(&ciphertext, contextInfo)
+	if err != nil {
+		t.Fatalf("NewDecryptingReader() failed: %s", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() failed: %s", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("round trip = %q, want %q", got, plaintext)
+	}
+}
+
+// TestStreamingHybridDecryptRejectsWrongPrefix checks that
+// NewDecryptingReader rejects a stream whose prefix doesn't match the
+// decrypting primitive set's primary key.
+func TestStreamingHybridDecryptRejectsWrongPrefix(t *testing.T) {
+	encPS := newFakeStreamingPrimitiveSet("abcde")
+	decPS := newFakeStreamingPrimitiveSet("fghij")
+
+	wshe, err := newStreamingHybridEncryptPrimitiveSet(encPS)
+	if err != nil {
+		t.Fatalf("newStreamingHybridEncryptPrimitiveSet() failed: %s", err)
+	}
+	wshd, err := newStreamingHybridDecryptPrimitiveSet(decPS)
+	if err != nil {
+		t.Fatalf("newStreamingHybridDecryptPrimitiveSet() failed: %s", err)
+	}
+
+	var ciphertext bytes.Buffer
+	w, err := wshe.NewEncryptingWriter(&ciphertext, nil)
+	if err != nil {
+		t.Fatalf("NewEncryptingWriter() failed: %s", err)
+	}
+	if _, err := w.Write([]byte("plaintext")); err != nil {
+		t.Fatalf("Write() failed: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %s", err)
+	}
+
+	if _, err := wshd.NewDecryptingReader(&ciphertext, nil); err == nil {
+		t.Error("NewDecryptingReader() succeeded with a mismatched key prefix, want error")
+	}
+}
+
+// TestDecryptWithKeyID checks the key-ID fast path's three cases: a hit that
+// decrypts through the matching entry, a miss when no entry exists for the
+// given key ID, and a miss when ciphertext carries a different key's prefix
+// than the one byKeyID resolves keyID to.
+func TestDecryptWithKeyID(t *testing.T) {
+	const prefix = "abcde"
+	plaintext := []byte("hello hybrid world")
+	ps := newFakeHybridPrimitiveSet(prefix, 1, plaintext)
+	wrapped, err := newWrappedHybridDecrypt(ps)
+	if err != nil {
+		t.Fatalf("newWrappedHybridDecrypt() failed: %s", err)
+	}
+
+	t.Run("hit", func(t *testing.T) {
+		ct := append([]byte(prefix), plaintext...)
+		got, err := wrapped.DecryptWithKeyID(ct, nil, 1)
+		if err != nil {
+			t.Fatalf("DecryptWithKeyID() failed: %s", err)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("DecryptWithKeyID() = %q, want %q", got, plaintext)
+		}
+	})
+
+	t.Run("no entry for key id", func(t *testing.T) {
+		ct := append([]byte(prefix), plaintext...)
+		if _, err := wrapped.DecryptWithKeyID(ct, nil, 2); err == nil {
+			t.Error("DecryptWithKeyID() succeeded for an unknown key id, want error")
+		}
+	})
+
+	t.Run("mismatched prefix", func(t *testing.T) {
+		ct := append([]byte("fghij"), plaintext...)
+		if _, err := wrapped.DecryptWithKeyID(ct, nil, 1); err == nil {
+			t.Error("DecryptWithKeyID() succeeded with a mismatched key prefix, want error")
+		}
+	})
+}
@@ -0,0 +1,54 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hpke
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// aeadParams describes an AEAD algorithm's key/nonce lengths, per
+// RFC 9180 Section 7.3, and how to instantiate a cipher.AEAD from a key.
+type aeadParams struct {
+	aeadID  uint16
+	keySize int
+	// nonceSize (Nn) is also the length of the key schedule's base_nonce.
+	nonceSize int
+	newAEAD   func(key []byte) (cipher.AEAD, error)
+}
+
+func newAEADParams(aeadID uint16) (*aeadParams, error) {
+	switch aeadID {
+	case AES128GCM:
+		return &aeadParams{aeadID: aeadID, keySize: 16, nonceSize: 12, newAEAD: newAESGCM}, nil
+	case AES256GCM:
+		return &aeadParams{aeadID: aeadID, keySize: 32, nonceSize: 12, newAEAD: newAESGCM}, nil
+	case ChaCha20Poly1305:
+		return &aeadParams{aeadID: aeadID, keySize: chacha20poly1305.KeySize, nonceSize: chacha20poly1305.NonceSize, newAEAD: chacha20poly1305.New}, nil
+	default:
+		return nil, fmt.Errorf("hpke: unsupported AEAD id %d", aeadID)
+	}
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
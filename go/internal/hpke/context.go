@@ -0,0 +1,179 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hpke
+
+import (
+	"fmt"
+)
+
+// Context is the encryption context established by SetupBaseS/SetupBaseR
+// (RFC 9180 Section 5.1). Its Seal/Open methods derive a fresh nonce for
+// every call from the context's base_nonce and an internal sequence number,
+// per Section 5.2; callers never supply or see a nonce directly.
+type Context struct {
+	aead      *aeadParams
+	key       []byte
+	baseNonce []byte
+	seq       uint64
+}
+
+// hpkeSuiteID is the "HPKE" suite_id of RFC 9180 Section 5.1, which keys the
+// key schedule's labeled extract/expand calls. It differs from the KEM-only
+// suite_id used inside DHKEM's own ExtractAndExpand.
+func hpkeSuiteID(p Params) []byte {
+	return concat([]byte("HPKE"), i2osp(int(p.KEMID), 2), i2osp(int(p.KDFID), 2), i2osp(int(p.AEADID), 2))
+}
+
+// keySchedule implements RFC 9180 Section 5.1's KeySchedule for mode_base,
+// i.e. with empty psk/psk_id.
+func keySchedule(p Params, kdf *hkdfParams, aead *aeadParams, sharedSecret, info []byte) (*Context, error) {
+	suiteID := hpkeSuiteID(p)
+
+	pskIDHash := kdf.labeledExtract(suiteID, nil, "psk_id_hash", nil)
+	infoHash := kdf.labeledExtract(suiteID, nil, "info_hash", info)
+	keyScheduleContext := concat([]byte{modeBase}, pskIDHash, infoHash)
+
+	secret := kdf.labeledExtract(suiteID, sharedSecret, "secret", nil)
+
+	key, err := kdf.labeledExpand(suiteID, secret, "key", keyScheduleContext, aead.keySize)
+	if err != nil {
+		return nil, err
+	}
+	baseNonce, err := kdf.labeledExpand(suiteID, secret, "base_nonce", keyScheduleContext, aead.nonceSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Context{aead: aead, key: key, baseNonce: baseNonce}, nil
+}
+
+// SetupBaseS implements RFC 9180 Section 5.1.1's SetupBaseS: it encapsulates
+// a shared secret for recipientPublicKey and sets up the sender's encryption
+// context bound to info.
+func SetupBaseS(p Params, recipientPublicKey, info []byte) (enc []byte, ctx *Context, err error) {
+	k, kdf, aead, err := newSuite(p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sharedSecret, enc, err := k.encapsulate(recipientPublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, err = keySchedule(p, kdf, aead, sharedSecret, info)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, ctx, nil
+}
+
+// SetupBaseR implements RFC 9180 Section 5.1.1's SetupBaseR: it decapsulates
+// the shared secret from enc using recipientPrivateKey and sets up the
+// recipient's decryption context bound to info.
+func SetupBaseR(p Params, enc, recipientPrivateKey, info []byte) (*Context, error) {
+	k, kdf, aead, err := newSuite(p)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedSecret, err := k.decapsulate(enc, recipientPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return keySchedule(p, kdf, aead, sharedSecret, info)
+}
+
+// EncapsulatedKeySize returns Nenc, the length in bytes of an encapsulated
+// key produced by SetupBaseS for the given KEM.
+func EncapsulatedKeySize(kemID uint16) (int, error) {
+	k, err := newKEM(kemID)
+	if err != nil {
+		return 0, err
+	}
+	return k.encapsulatedKeySize(), nil
+}
+
+func newSuite(p Params) (kem, *hkdfParams, *aeadParams, error) {
+	k, err := newKEM(p.KEMID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	kdf, err := newKDF(p.KDFID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	aead, err := newAEADParams(p.AEADID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return k, kdf, aead, nil
+}
+
+// nextNonce computes the per-call nonce of RFC 9180 Section 5.2:
+// base_nonce XOR I2OSP(seq, Nn).
+func (c *Context) nextNonce() ([]byte, error) {
+	// Every standard HPKE AEAD uses a >=96-bit nonce, so the 64-bit sequence
+	// counter below can never actually wrap; the check exists for
+	// correctness against the spec rather than against reachable input.
+	if c.seq == ^uint64(0) {
+		return nil, fmt.Errorf("hpke: message limit reached for this context")
+	}
+
+	seqBytes := i2osp(int(c.seq), len(c.baseNonce))
+	nonce := make([]byte, len(c.baseNonce))
+	for i := range nonce {
+		nonce[i] = c.baseNonce[i] ^ seqBytes[i]
+	}
+	return nonce, nil
+}
+
+// Seal encrypts pt, authenticating aad, using the next nonce derived from
+// this context's base_nonce and internal sequence number.
+func (c *Context) Seal(aad, pt []byte) ([]byte, error) {
+	nonce, err := c.nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	a, err := c.aead.newAEAD(c.key)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: failed to instantiate AEAD: %s", err)
+	}
+
+	ct := a.Seal(nil, nonce, pt, aad)
+	c.seq++
+	return ct, nil
+}
+
+// Open decrypts ct, authenticating aad, using the next nonce derived from
+// this context's base_nonce and internal sequence number.
+func (c *Context) Open(aad, ct []byte) ([]byte, error) {
+	nonce, err := c.nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	a, err := c.aead.newAEAD(c.key)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: failed to instantiate AEAD: %s", err)
+	}
+
+	pt, err := a.Open(nil, nonce, ct, aad)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: decryption failed: %s", err)
+	}
+	c.seq++
+	return pt, nil
+}
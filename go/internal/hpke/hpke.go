@@ -0,0 +1,57 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package hpke provides the primitives needed to implement RFC 9180's
+// "Hybrid Public Key Encryption", mode_base, KEM/KDF/AEAD combination only.
+// It is not meant to be used directly by consumers of this repository;
+// the hybrid package exposes it through tink.HybridEncrypt/tink.HybridDecrypt.
+package hpke
+
+import "fmt"
+
+// mode_base from RFC 9180 Section 5, encoded as the mode byte that leads the
+// key schedule context.
+const modeBase byte = 0x00
+
+// KEM IDs from RFC 9180 Section 7.1.
+const (
+	X25519HKDFSHA256 uint16 = 0x0020
+	P256HKDFSHA256   uint16 = 0x0010
+)
+
+// KDF IDs from RFC 9180 Section 7.2.
+const (
+	HKDFSHA256 uint16 = 0x0001
+	HKDFSHA384 uint16 = 0x0002
+	HKDFSHA512 uint16 = 0x0003
+)
+
+// AEAD IDs from RFC 9180 Section 7.3.
+const (
+	AES128GCM        uint16 = 0x0001
+	AES256GCM        uint16 = 0x0002
+	ChaCha20Poly1305 uint16 = 0x0003
+)
+
+// Params bundles the KEM, KDF, and AEAD algorithm identifiers that identify
+// an HPKE ciphersuite.
+type Params struct {
+	KEMID  uint16
+	KDFID  uint16
+	AEADID uint16
+}
+
+func (p Params) String() string {
+	return fmt.Sprintf("KEM: %d, KDF: %d, AEAD: %d", p.KEMID, p.KDFID, p.AEADID)
+}
@@ -0,0 +1,127 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hpke
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// The values below are taken verbatim from RFC 9180 Appendix A.1.1's test
+// vector for mode_base, DHKEM(X25519, HKDF-SHA256), HKDF-SHA256,
+// AES-128-GCM (kem_id=0x0020, kdf_id=0x0001, aead_id=0x0001).
+const (
+	rfc9180X25519Info         = "4f6465206f6e2061204772656369616e2055726e"
+	rfc9180X25519PkRm         = "3948cfe0ad1ddb695d780e59077195da6c56506b027329794ab02bca80815c4d"
+	rfc9180X25519SkRm         = "4612c550263fc8ad58375df3f557aac531d26850903e55a9f23f21d8534e8ac8"
+	rfc9180X25519Enc          = "37fda3567bdbd628e88668c3c8d7e97d1d1253b6d4ea6d44c150f741f1bf4431"
+	rfc9180X25519SharedSecret = "fe0e18c9f024ce43799ae393c7e8fe8fce9d218875e8227b0187c04e7d2ea1fc"
+	rfc9180X25519Key          = "4531685d41d65f03dc48f6b8302c05b0"
+	rfc9180X25519BaseNonce    = "56d890e5accaaf011cff4b7d"
+)
+
+// rfc9180X25519Encryptions are the first few entries of that vector's
+// "encryptions" array, each independently AEAD-sealed against the same
+// context with an incrementing sequence number.
+var rfc9180X25519Encryptions = []struct {
+	aad, ct, pt string
+}{
+	{
+		aad: "436f756e742d30",
+		ct:  "f938558b5d72f1a23810b4be2ab4f84331acc02fc97babc53a52ae8218a355a96d8770ac83d07bea87e13c512a",
+		pt:  "4265617574792069732074727574682c20747275746820626561757479",
+	},
+	{
+		aad: "436f756e742d31",
+		ct:  "af2d7e9ac9ae7e270f46ba1f975be53c09f8d875bdc8535458c2494e8a6eab251c03d0c22a56b8ca42c2063b84",
+		pt:  "4265617574792069732074727574682c20747275746820626561757479",
+	},
+	{
+		aad: "436f756e742d32",
+		ct:  "498dfcabd92e8acedc281e85af1cb4e3e31c7dc394a1ca20e173cb72516491588d96a19ad4a683518973dcc180",
+		pt:  "4265617574792069732074727574682c20747275746820626561757479",
+	},
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("hex.DecodeString(%q) failed: %s", s, err)
+	}
+	return b
+}
+
+// TestX25519DecapsulateRFC9180Vector checks that decapsulating RFC 9180's
+// own enc with its own recipient private key reproduces its shared_secret,
+// exercising the KEM's ExtractAndExpand exactly as SetupBaseR does.
+func TestX25519DecapsulateRFC9180Vector(t *testing.T) {
+	enc := mustDecodeHex(t, rfc9180X25519Enc)
+	skRm := mustDecodeHex(t, rfc9180X25519SkRm)
+	wantSharedSecret := mustDecodeHex(t, rfc9180X25519SharedSecret)
+
+	k, err := newKEM(X25519HKDFSHA256)
+	if err != nil {
+		t.Fatalf("newKEM() failed: %s", err)
+	}
+
+	got, err := k.decapsulate(enc, skRm)
+	if err != nil {
+		t.Fatalf("decapsulate() failed: %s", err)
+	}
+	if !bytes.Equal(got, wantSharedSecret) {
+		t.Errorf("decapsulate() = %x, want %x", got, wantSharedSecret)
+	}
+}
+
+// TestSetupBaseRRFC9180Vector checks that SetupBaseR, fed the test vector's
+// enc/skRm/info, derives the same key and base_nonce as the vector, and
+// that Context.Open recovers the vector's known plaintexts from its known
+// ciphertexts at the expected sequence numbers.
+func TestSetupBaseRRFC9180Vector(t *testing.T) {
+	enc := mustDecodeHex(t, rfc9180X25519Enc)
+	skRm := mustDecodeHex(t, rfc9180X25519SkRm)
+	info := mustDecodeHex(t, rfc9180X25519Info)
+	wantKey := mustDecodeHex(t, rfc9180X25519Key)
+	wantBaseNonce := mustDecodeHex(t, rfc9180X25519BaseNonce)
+
+	params := Params{KEMID: X25519HKDFSHA256, KDFID: HKDFSHA256, AEADID: AES128GCM}
+	ctx, err := SetupBaseR(params, enc, skRm, info)
+	if err != nil {
+		t.Fatalf("SetupBaseR() failed: %s", err)
+	}
+
+	if !bytes.Equal(ctx.key, wantKey) {
+		t.Errorf("key = %x, want %x", ctx.key, wantKey)
+	}
+	if !bytes.Equal(ctx.baseNonce, wantBaseNonce) {
+		t.Errorf("base_nonce = %x, want %x", ctx.baseNonce, wantBaseNonce)
+	}
+
+	for i, e := range rfc9180X25519Encryptions {
+		aad := mustDecodeHex(t, e.aad)
+		ct := mustDecodeHex(t, e.ct)
+		wantPT := mustDecodeHex(t, e.pt)
+
+		gotPT, err := ctx.Open(aad, ct)
+		if err != nil {
+			t.Fatalf("Open() for encryptions[%d] failed: %s", i, err)
+		}
+		if !bytes.Equal(gotPT, wantPT) {
+			t.Errorf("Open() for encryptions[%d] = %x, want %x", i, gotPT, wantPT)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hpke
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+)
+
+// hpkeVersion is the "HPKE-v1" suite_id/label prefix required by every
+// LabeledExtract/LabeledExpand call, per RFC 9180 Section 4.
+var hpkeVersion = []byte("HPKE-v1")
+
+// hkdfParams implements RFC 5869 HKDF-Extract/Expand, and the
+// LabeledExtract/LabeledExpand wrappers of RFC 9180 Section 4.1, for a given
+// hash function.
+type hkdfParams struct {
+	kdfID  uint16
+	hashFn func() hash.Hash
+}
+
+func newHKDF(kdfID uint16) *hkdfParams {
+	switch kdfID {
+	case HKDFSHA256:
+		return &hkdfParams{kdfID: kdfID, hashFn: sha256.New}
+	case HKDFSHA384:
+		return &hkdfParams{kdfID: kdfID, hashFn: sha512.New384}
+	case HKDFSHA512:
+		return &hkdfParams{kdfID: kdfID, hashFn: sha512.New}
+	default:
+		return nil
+	}
+}
+
+func newKDF(kdfID uint16) (*hkdfParams, error) {
+	k := newHKDF(kdfID)
+	if k == nil {
+		return nil, fmt.Errorf("hpke: unsupported KDF id %d", kdfID)
+	}
+	return k, nil
+}
+
+func (k *hkdfParams) nH() int { return k.hashFn().Size() }
+
+// extract implements RFC 5869's HKDF-Extract(salt, ikm) = HMAC-Hash(salt, ikm).
+func (k *hkdfParams) extract(salt, ikm []byte) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, k.nH())
+	}
+	mac := hmac.New(k.hashFn, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+// expand implements RFC 5869's HKDF-Expand(prk, info, length).
+func (k *hkdfParams) expand(prk, info []byte, length int) ([]byte, error) {
+	nH := k.nH()
+	if length > 255*nH {
+		return nil, fmt.Errorf("hpke: requested expand length %d exceeds 255*%d", length, nH)
+	}
+
+	var t, out []byte
+	for i := byte(1); len(out) < length; i++ {
+		mac := hmac.New(k.hashFn, prk)
+		mac.Write(t)
+		mac.Write(info)
+		mac.Write([]byte{i})
+		t = mac.Sum(nil)
+		out = append(out, t...)
+	}
+	return out[:length], nil
+}
+
+func (k *hkdfParams) labeledExtract(suiteID, salt []byte, label string, ikm []byte) []byte {
+	labeledIKM := concat(hpkeVersion, suiteID, []byte(label), ikm)
+	return k.extract(salt, labeledIKM)
+}
+
+func (k *hkdfParams) labeledExpand(suiteID, prk []byte, label string, info []byte, length int) ([]byte, error) {
+	labeledInfo := concat(i2osp(length, 2), hpkeVersion, suiteID, []byte(label), info)
+	return k.expand(prk, labeledInfo, length)
+}
+
+// i2osp is the Integer-to-Octet-String-Primitive from RFC 8017 Section 4.1,
+// encoding n as a big-endian byte string of the given length.
+func i2osp(n, length int) []byte {
+	b := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		b[i] = byte(n)
+		n >>= 8
+	}
+	return b
+}
+
+func concat(parts ...[]byte) []byte {
+	var n int
+	for _, p := range parts {
+		n += len(p)
+	}
+	out := make([]byte, 0, n)
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
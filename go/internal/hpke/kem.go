@@ -0,0 +1,68 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hpke
+
+import (
+	"crypto/ecdh"
+	"fmt"
+)
+
+// kem is a RFC 9180 Section 4 Key Encapsulation Mechanism: it derives a
+// shared secret between a sender and a recipient via the recipient's public
+// key, and encodes that derivation as a fixed-length encapsulated key (enc)
+// that the recipient can use, together with its private key, to recover the
+// same shared secret.
+type kem interface {
+	// encapsulate generates an ephemeral key pair, uses it together with
+	// recipientPublicKey to derive sharedSecret via the KEM's DH and KDF, and
+	// returns sharedSecret alongside its encapsulation enc.
+	encapsulate(recipientPublicKey []byte) (sharedSecret, enc []byte, err error)
+
+	// decapsulate recovers the shared secret encapsulated in enc using
+	// recipientPrivateKey.
+	decapsulate(enc, recipientPrivateKey []byte) ([]byte, error)
+
+	// encapsulatedKeySize returns Nenc, the fixed length in bytes of enc.
+	encapsulatedKeySize() int
+
+	// suiteID returns the five-byte KEM suite ID ("KEM" || kemID) used to key
+	// the labeled extract/expand calls of RFC 9180 Section 4.1.
+	suiteID() []byte
+}
+
+func newKEM(kemID uint16) (kem, error) {
+	switch kemID {
+	case X25519HKDFSHA256:
+		return &dhkemX25519{kdf: newHKDF(HKDFSHA256)}, nil
+	case P256HKDFSHA256:
+		// An uncompressed P-256 point is 1 (format byte) + 2*32 (X || Y) bytes.
+		return &dhkemNISTCurve{curve: ecdh.P256(), kdf: newHKDF(HKDFSHA256), kemID: P256HKDFSHA256, nSecret: 32, nEnc: 65}, nil
+	default:
+		return nil, fmt.Errorf("hpke: unsupported KEM id %d", kemID)
+	}
+}
+
+func kemSuiteID(kemID uint16) []byte {
+	return []byte{'K', 'E', 'M', byte(kemID >> 8), byte(kemID)}
+}
+
+// extractAndExpandSharedSecret implements RFC 9180 Section 4.1's
+// ExtractAndExpand: it runs the KEM's KDF over the DH output and the
+// kem_context (the concatenation of the ephemeral and recipient public
+// keys) to derive an Nsecret-byte shared secret.
+func extractAndExpandSharedSecret(kdf *hkdfParams, suiteID []byte, dh, kemContext []byte, nSecret int) ([]byte, error) {
+	eaePRK := kdf.labeledExtract(suiteID, nil, "eae_prk", dh)
+	return kdf.labeledExpand(suiteID, eaePRK, "shared_secret", kemContext, nSecret)
+}
@@ -0,0 +1,89 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hpke
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"fmt"
+)
+
+// dhkemNISTCurve implements the DHKEM(P-256, HKDF-SHA256) KEM of
+// RFC 9180 Section 7.1. It is parameterized on the underlying NIST curve and
+// KEM id so that P-384/P-521 variants can reuse it if this package grows to
+// support them.
+type dhkemNISTCurve struct {
+	curve   ecdh.Curve
+	kdf     *hkdfParams
+	kemID   uint16
+	nSecret int
+	// nEnc is the length in bytes of an uncompressed point on curve, i.e.
+	// the length of both the encapsulated key and the encoded public key.
+	nEnc int
+}
+
+func (k *dhkemNISTCurve) suiteID() []byte { return kemSuiteID(k.kemID) }
+
+func (k *dhkemNISTCurve) encapsulatedKeySize() int { return k.nEnc }
+
+func (k *dhkemNISTCurve) encapsulate(recipientPublicKey []byte) ([]byte, []byte, error) {
+	if len(recipientPublicKey) != k.nEnc {
+		return nil, nil, fmt.Errorf("hpke: invalid recipient public key length %d, want %d", len(recipientPublicKey), k.nEnc)
+	}
+	pkR, err := k.curve.NewPublicKey(recipientPublicKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hpke: invalid recipient public key: %s", err)
+	}
+
+	skE, err := k.curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hpke: failed to generate ephemeral key: %s", err)
+	}
+
+	dh, err := skE.ECDH(pkR)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hpke: key agreement failed: %s", err)
+	}
+
+	enc := skE.PublicKey().Bytes()
+	kemContext := append(append([]byte{}, enc...), recipientPublicKey...)
+	sharedSecret, err := extractAndExpandSharedSecret(k.kdf, k.suiteID(), dh, kemContext, k.nSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sharedSecret, enc, nil
+}
+
+func (k *dhkemNISTCurve) decapsulate(enc, recipientPrivateKey []byte) ([]byte, error) {
+	if len(enc) != k.nEnc {
+		return nil, fmt.Errorf("hpke: invalid encapsulated key length %d, want %d", len(enc), k.nEnc)
+	}
+	pkE, err := k.curve.NewPublicKey(enc)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: invalid encapsulated key: %s", err)
+	}
+	skR, err := k.curve.NewPrivateKey(recipientPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: invalid recipient private key: %s", err)
+	}
+
+	dh, err := skR.ECDH(pkE)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: key agreement failed: %s", err)
+	}
+
+	kemContext := append(append([]byte{}, enc...), skR.PublicKey().Bytes()...)
+	return extractAndExpandSharedSecret(k.kdf, k.suiteID(), dh, kemContext, k.nSecret)
+}
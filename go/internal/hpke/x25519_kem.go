@@ -0,0 +1,90 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hpke
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"fmt"
+)
+
+// dhkemX25519 implements the DHKEM(X25519, HKDF-SHA256) KEM of RFC 9180
+// Section 7.1.
+type dhkemX25519 struct {
+	kdf *hkdfParams
+}
+
+const (
+	// x25519EncSize is Nenc, the length of an encapsulated key, which for
+	// X25519 is also the length of an encoded public key.
+	x25519EncSize = 32
+	// x25519NSecret is Nsecret, the length of the shared secret this KEM
+	// derives.
+	x25519NSecret = 32
+)
+
+func (k *dhkemX25519) suiteID() []byte { return kemSuiteID(X25519HKDFSHA256) }
+
+func (k *dhkemX25519) encapsulatedKeySize() int { return x25519EncSize }
+
+func (k *dhkemX25519) encapsulate(recipientPublicKey []byte) ([]byte, []byte, error) {
+	if len(recipientPublicKey) != x25519EncSize {
+		return nil, nil, fmt.Errorf("hpke: invalid recipient public key length %d, want %d", len(recipientPublicKey), x25519EncSize)
+	}
+
+	curve := ecdh.X25519()
+	pkR, err := curve.NewPublicKey(recipientPublicKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hpke: invalid X25519 recipient public key: %s", err)
+	}
+
+	skE, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hpke: failed to generate X25519 ephemeral key: %s", err)
+	}
+
+	dh, err := skE.ECDH(pkR)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hpke: X25519 key agreement failed: %s", err)
+	}
+
+	enc := skE.PublicKey().Bytes()
+	kemContext := append(append([]byte{}, enc...), recipientPublicKey...)
+	sharedSecret, err := extractAndExpandSharedSecret(k.kdf, k.suiteID(), dh, kemContext, x25519NSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sharedSecret, enc, nil
+}
+
+func (k *dhkemX25519) decapsulate(enc, recipientPrivateKey []byte) ([]byte, error) {
+	curve := ecdh.X25519()
+	pkE, err := curve.NewPublicKey(enc)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: invalid X25519 encapsulated key: %s", err)
+	}
+	skR, err := curve.NewPrivateKey(recipientPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: invalid X25519 recipient private key: %s", err)
+	}
+
+	dh, err := skR.ECDH(pkE)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: X25519 key agreement failed: %s", err)
+	}
+
+	kemContext := append(append([]byte{}, enc...), skR.PublicKey().Bytes()...)
+	return extractAndExpandSharedSecret(k.kdf, k.suiteID(), dh, kemContext, x25519NSecret)
+}